@@ -9,14 +9,59 @@ import (
 
 // Actions provides convenience methods for queueing actions to be sent in a batch.
 type Actions struct {
-	info         client.AgentInfo
-	actions      []*api.Action
-	errorHandler ActionErrorHandler
+	info          client.AgentInfo
+	actions       []*api.Action
+	pending       []pendingAction
+	nextID        ActionID
+	errorHandler  ActionErrorHandler
+	eventHandlers []ActionEventHandler
 }
 
 // ActionErrorHandler is the handler function type for action errors.
 type ActionErrorHandler func(action *api.Action, result api.ActionResult)
 
+// ActionID identifies a single action enqueued with one of the Actions methods. It's
+// returned from the enqueuing call and shows up again on the ActionEvent delivered
+// for that action, so the two can be correlated.
+type ActionID uint64
+
+// ActionKind categorizes the raw action an ActionEvent was generated for.
+type ActionKind int
+
+// Kinds of actions an ActionEvent can report on.
+const (
+	ActionKindChat ActionKind = iota
+	ActionKindCameraMove
+	ActionKindUnitCommand
+)
+
+// ActionEvent reports the outcome of a single previously enqueued action once its
+// batch has been sent. Target is nil, an api.UnitTag, or an *api.Point2D depending on
+// Kind and how the action was targeted. Frame is the game loop the action was sent on,
+// as reported by the observation current at Send() time.
+type ActionEvent struct {
+	ID      ActionID
+	Kind    ActionKind
+	Units   []api.UnitTag
+	Ability api.AbilityID
+	Target  interface{}
+	Result  api.ActionResult
+	Frame   uint32
+}
+
+// ActionEventHandler is the handler function type for per-action result delivery.
+type ActionEventHandler func(ev ActionEvent)
+
+// pendingAction is the bookkeeping kept alongside a queued *api.Action so that a
+// matching ActionEvent can be produced once the batch comes back from SendActions.
+type pendingAction struct {
+	id      ActionID
+	kind    ActionKind
+	units   []api.UnitTag
+	ability api.AbilityID
+	target  interface{}
+}
+
 // NewActions creates a new Actions manager. It's Send() method is registered to be
 // automatcially called before each client Step().
 func NewActions(info client.AgentInfo) *Actions {
@@ -37,6 +82,13 @@ func (a *Actions) LogActionErrors() {
 	})
 }
 
+// OnActionEvent registers a handler that's called with the ActionEvent for every
+// action once its batch has been sent, successful or not. Multiple handlers may be
+// registered; all of them are called for every event.
+func (a *Actions) OnActionEvent(handler ActionEventHandler) {
+	a.eventHandlers = append(a.eventHandlers, handler)
+}
+
 // Send is called automatically to submit queued actions before each Step(). It may also be
 // called manually at any point to send all queued actions immediately.
 func (a *Actions) Send() {
@@ -44,41 +96,82 @@ func (a *Actions) Send() {
 		return
 	}
 
-	results := a.info.SendActions(a.actions)
-	if a.errorHandler != nil {
-		for i, r := range results {
-			if r != api.ActionResult_Success {
-				a.errorHandler(a.actions[i], r)
-			}
+	actions, pending := a.actions, a.pending
+	a.actions, a.pending = nil, nil
+
+	frame := a.info.Observation().GetObservation().GetGameLoop()
+	results := a.info.SendActions(actions)
+	for i, r := range results {
+		p := pending[i]
+		ev := ActionEvent{
+			ID:      p.id,
+			Kind:    p.kind,
+			Units:   p.units,
+			Ability: p.ability,
+			Target:  p.target,
+			Result:  r,
+			Frame:   frame,
+		}
+		for _, h := range a.eventHandlers {
+			h(ev)
+		}
+		if r != api.ActionResult_Success && a.errorHandler != nil {
+			a.errorHandler(actions[i], r)
 		}
 	}
-	a.actions = nil
+}
+
+// SendAndWait sends all queued actions immediately and returns the ActionEvent for
+// each one, for use in test code that needs the result synchronously rather than
+// through OnActionEvent.
+func (a *Actions) SendAndWait() []ActionEvent {
+	if len(a.actions) == 0 {
+		return nil
+	}
+
+	var events []ActionEvent
+	a.OnActionEvent(func(ev ActionEvent) {
+		events = append(events, ev)
+	})
+	a.Send()
+	a.eventHandlers = a.eventHandlers[:len(a.eventHandlers)-1]
+	return events
+}
+
+// enqueue appends action to the batch along with the bookkeeping needed to produce
+// its ActionEvent once the batch is sent, and returns its ActionID.
+func (a *Actions) enqueue(action *api.Action, kind ActionKind, units []api.UnitTag, ability api.AbilityID, target interface{}) ActionID {
+	a.nextID++
+	id := a.nextID
+	a.actions = append(a.actions, action)
+	a.pending = append(a.pending, pendingAction{id: id, kind: kind, units: units, ability: ability, target: target})
+	return id
 }
 
 // Chat sends a message that all players can see.
-func (a *Actions) Chat(msg string) {
-	a.actions = append(a.actions, &api.Action{
+func (a *Actions) Chat(msg string) ActionID {
+	return a.enqueue(&api.Action{
 		ActionChat: &api.ActionChat{
 			Channel: api.ActionChat_Broadcast,
 			Message: msg,
 		},
-	})
+	}, ActionKindChat, nil, 0, nil)
 }
 
 // ChatTeam sends a message that only teammates (and observers) can see.
-func (a *Actions) ChatTeam(msg string) {
-	a.actions = append(a.actions, &api.Action{
+func (a *Actions) ChatTeam(msg string) ActionID {
+	return a.enqueue(&api.Action{
 		ActionChat: &api.ActionChat{
 			Channel: api.ActionChat_Team,
 			Message: msg,
 		},
-	})
+	}, ActionKindChat, nil, 0, nil)
 }
 
 // MoveCamera repositions the camera to center on the target point.
-func (a *Actions) MoveCamera(pt api.Point2D) {
+func (a *Actions) MoveCamera(pt api.Point2D) ActionID {
 	p := pt.ToPoint()
-	a.actions = append(a.actions, &api.Action{
+	return a.enqueue(&api.Action{
 		ActionRaw: &api.ActionRaw{
 			Action: &api.ActionRaw_CameraMove{
 				CameraMove: &api.ActionRawCameraMove{
@@ -86,132 +179,140 @@ func (a *Actions) MoveCamera(pt api.Point2D) {
 				},
 			},
 		},
-	})
+	}, ActionKindCameraMove, nil, 0, nil)
 }
 
 // UnitOrder orders a unit to use an ability.
-func (a *Actions) UnitOrder(u Unit, ability api.AbilityID) {
-	a.unitsOrder([]api.UnitTag{u.GetTag()}, ability)
+func (a *Actions) UnitOrder(u Unit, ability api.AbilityID) ActionID {
+	return a.unitsOrder([]api.UnitTag{u.GetTag()}, ability)
 }
 
 // UnitOrderTarget orders a unit to use an ability on a target unit.
-func (a *Actions) UnitOrderTarget(u Unit, ability api.AbilityID, target Unit) {
-	a.unitsOrderTarget([]api.UnitTag{u.GetTag()}, ability, target)
+func (a *Actions) UnitOrderTarget(u Unit, ability api.AbilityID, target Unit) ActionID {
+	return a.unitsOrderTarget([]api.UnitTag{u.GetTag()}, ability, target)
 }
 
 // UnitOrderPos orders a unit to use an ability at a target location.
-func (a *Actions) UnitOrderPos(u Unit, ability api.AbilityID, target *api.Point2D) {
-	a.unitsOrderPos([]api.UnitTag{u.GetTag()}, ability, target)
+func (a *Actions) UnitOrderPos(u Unit, ability api.AbilityID, target *api.Point2D) ActionID {
+	return a.unitsOrderPos([]api.UnitTag{u.GetTag()}, ability, target)
 }
 
 // UnitsOrder orders units to all use an ability.
-func (a *Actions) UnitsOrder(units Units, ability api.AbilityID) {
-	a.unitsOrder(units.Tags(), ability)
+func (a *Actions) UnitsOrder(units Units, ability api.AbilityID) ActionID {
+	return a.unitsOrder(units.Tags(), ability)
 }
 
 // UnitsOrderTarget orders units to all use an ability on a target unit.
-func (a *Actions) UnitsOrderTarget(units Units, ability api.AbilityID, target Unit) {
-	a.unitsOrderTarget(units.Tags(), ability, target)
+func (a *Actions) UnitsOrderTarget(units Units, ability api.AbilityID, target Unit) ActionID {
+	return a.unitsOrderTarget(units.Tags(), ability, target)
 }
 
 // UnitsOrderPos orders units to all use an ability at a target location.
-func (a *Actions) UnitsOrderPos(units Units, ability api.AbilityID, target *api.Point2D) {
-	a.unitsOrderPos(units.Tags(), ability, target)
+func (a *Actions) UnitsOrderPos(units Units, ability api.AbilityID, target *api.Point2D) ActionID {
+	return a.unitsOrderPos(units.Tags(), ability, target)
 }
 
 // unitsOrder orders units to all use an ability.
-func (a *Actions) unitsOrder(unitTags []api.UnitTag, ability api.AbilityID) {
+func (a *Actions) unitsOrder(unitTags []api.UnitTag, ability api.AbilityID) ActionID {
 	if len(unitTags) == 0 {
-		return
+		return 0
 	}
 
-	a.unitOrder(&api.ActionRawUnitCommand{
+	return a.unitOrder(&api.ActionRawUnitCommand{
 		AbilityId: ability,
 		UnitTags:  unitTags,
-	})
+	}, unitTags, ability, nil)
 }
 
 // unitsOrderTarget orders units to all use an ability on a target unit.
-func (a *Actions) unitsOrderTarget(unitTags []api.UnitTag, ability api.AbilityID, target Unit) {
+func (a *Actions) unitsOrderTarget(unitTags []api.UnitTag, ability api.AbilityID, target Unit) ActionID {
 	if len(unitTags) == 0 {
-		return
+		return 0
 	}
 
-	a.unitOrder(&api.ActionRawUnitCommand{
+	targetTag := target.GetTag()
+	return a.unitOrder(&api.ActionRawUnitCommand{
 		AbilityId: ability,
 		UnitTags:  unitTags,
 		Target: &api.ActionRawUnitCommand_TargetUnitTag{
-			TargetUnitTag: target.GetTag(),
+			TargetUnitTag: targetTag,
 		},
-	})
+	}, unitTags, ability, targetTag)
 }
 
 // unitsOrderPos orders units to all use an ability at a target location.
-func (a *Actions) unitsOrderPos(unitTags []api.UnitTag, ability api.AbilityID, target *api.Point2D) {
+func (a *Actions) unitsOrderPos(unitTags []api.UnitTag, ability api.AbilityID, target *api.Point2D) ActionID {
 	if len(unitTags) == 0 {
-		return
+		return 0
 	}
 
-	a.unitOrder(&api.ActionRawUnitCommand{
+	return a.unitOrder(&api.ActionRawUnitCommand{
 		AbilityId: ability,
 		UnitTags:  unitTags,
 		Target: &api.ActionRawUnitCommand_TargetWorldSpacePos{
 			TargetWorldSpacePos: target,
 		},
-	})
+	}, unitTags, ability, target)
 }
 
-// unitOrder finishes wrapping an ActionRawUnitCommand and adds it to the command list.
-func (a *Actions) unitOrder(cmd *api.ActionRawUnitCommand) {
-	a.actions = append(a.actions, &api.Action{
+// unitOrder finishes wrapping an ActionRawUnitCommand, adds it to the command list,
+// and returns its ActionID.
+func (a *Actions) unitOrder(cmd *api.ActionRawUnitCommand, units []api.UnitTag, ability api.AbilityID, target interface{}) ActionID {
+	return a.enqueue(&api.Action{
 		ActionRaw: &api.ActionRaw{
 			Action: &api.ActionRaw_UnitCommand{
 				UnitCommand: cmd,
 			},
 		},
-	})
+	}, ActionKindUnitCommand, units, ability, target)
 }
 
 // Convenience methods for giving orders directly to units:
 
 // Order ...
-func (units Units) Order(ability api.AbilityID) {
+func (units Units) Order(ability api.AbilityID) ActionID {
 	if len(units.raw) > 0 {
-		units.ctx.bot.unitsOrder(units.Tags(), ability)
+		return units.ctx.bot.unitsOrder(units.Tags(), ability)
 	}
+	return 0
 }
 
 // OrderTarget ...
-func (units Units) OrderTarget(ability api.AbilityID, target Unit) {
+func (units Units) OrderTarget(ability api.AbilityID, target Unit) ActionID {
 	if len(units.raw) > 0 {
-		units.ctx.bot.unitsOrderTarget(units.Tags(), ability, target)
+		return units.ctx.bot.unitsOrderTarget(units.Tags(), ability, target)
 	}
+	return 0
 }
 
 // OrderPos ...
-func (units Units) OrderPos(ability api.AbilityID, target *api.Point2D) {
+func (units Units) OrderPos(ability api.AbilityID, target *api.Point2D) ActionID {
 	if len(units.raw) > 0 {
-		units.ctx.bot.unitsOrderPos(units.Tags(), ability, target)
+		return units.ctx.bot.unitsOrderPos(units.Tags(), ability, target)
 	}
+	return 0
 }
 
 // Order ...
-func (u Unit) Order(ability api.AbilityID) {
+func (u Unit) Order(ability api.AbilityID) ActionID {
 	if !u.IsNil() {
-		u.ctx.bot.UnitOrder(u, ability)
+		return u.ctx.bot.UnitOrder(u, ability)
 	}
+	return 0
 }
 
 // OrderTarget ...
-func (u Unit) OrderTarget(ability api.AbilityID, target Unit) {
+func (u Unit) OrderTarget(ability api.AbilityID, target Unit) ActionID {
 	if !u.IsNil() {
-		u.ctx.bot.UnitOrderTarget(u, ability, target)
+		return u.ctx.bot.UnitOrderTarget(u, ability, target)
 	}
+	return 0
 }
 
 // OrderPos ...
-func (u Unit) OrderPos(ability api.AbilityID, target *api.Point2D) {
+func (u Unit) OrderPos(ability api.AbilityID, target *api.Point2D) ActionID {
 	if !u.IsNil() {
-		u.ctx.bot.UnitOrderPos(u, ability, target)
+		return u.ctx.bot.UnitOrderPos(u, ability, target)
 	}
+	return 0
 }
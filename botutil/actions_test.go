@@ -0,0 +1,77 @@
+package botutil
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestActionsSendDemuxesPerActionEvents(t *testing.T) {
+	info := &fakeAgentInfo{
+		results: []api.ActionResult{api.ActionResult_Success, api.ActionResult_CouldNotFindTarget},
+		obs: &api.ResponseObservation{
+			Observation: &api.Observation{GameLoop: 42},
+		},
+	}
+	a := &Actions{info: info}
+
+	chatID := a.Chat("gg")
+	orderID := a.unitsOrder([]api.UnitTag{7}, 66)
+
+	var events []ActionEvent
+	a.OnActionEvent(func(ev ActionEvent) {
+		events = append(events, ev)
+	})
+	a.Send()
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].ID != chatID || events[1].ID != orderID {
+		t.Errorf("expected events to carry back their enqueue-time ActionIDs, got %+v", events)
+	}
+	if events[0].Result != api.ActionResult_Success || events[1].Result != api.ActionResult_CouldNotFindTarget {
+		t.Errorf("results not demuxed in enqueue order: %+v", events)
+	}
+	if events[0].Frame != 42 || events[1].Frame != 42 {
+		t.Errorf("expected both events stamped with the observation's game loop, got %+v", events)
+	}
+	if events[1].Kind != ActionKindUnitCommand || events[1].Ability != 66 {
+		t.Errorf("expected the unit command's kind/ability to round-trip, got %+v", events[1])
+	}
+
+	if len(a.actions) != 0 || len(a.pending) != 0 {
+		t.Error("expected Send to clear the queue")
+	}
+}
+
+func TestActionsSendAndWait(t *testing.T) {
+	info := &fakeAgentInfo{
+		results: []api.ActionResult{api.ActionResult_Success},
+		obs: &api.ResponseObservation{
+			Observation: &api.Observation{GameLoop: 1},
+		},
+	}
+	a := &Actions{info: info}
+
+	a.Chat("gl hf")
+	events := a.SendAndWait()
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if len(a.eventHandlers) != 0 {
+		t.Errorf("expected SendAndWait to unregister its temporary handler, got %d handlers", len(a.eventHandlers))
+	}
+}
+
+func TestActionsSkipsZeroLengthUnitOrders(t *testing.T) {
+	a := &Actions{info: &fakeAgentInfo{}}
+
+	if id := a.unitsOrder(nil, 1); id != 0 {
+		t.Errorf("expected a unit order with no units to return ActionID 0, got %d", id)
+	}
+	if len(a.actions) != 0 {
+		t.Error("expected no action to be queued for an empty unit set")
+	}
+}
@@ -0,0 +1,45 @@
+package botutil
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// fakeAgentInfo is a minimal client.AgentInfo double for exercising code built on top
+// of it without a live game connection: it records registered callbacks and hands
+// back scripted SendActions results and a scripted observation.
+type fakeAgentInfo struct {
+	player  api.PlayerID
+	obs     *api.ResponseObservation
+	results []api.ActionResult
+
+	sent             []*api.Action
+	observationHooks []func()
+}
+
+func (f *fakeAgentInfo) OnBeforeStep(func()) {}
+func (f *fakeAgentInfo) OnAfterStep(func())  {}
+
+func (f *fakeAgentInfo) OnObservation(fn func()) {
+	f.observationHooks = append(f.observationHooks, fn)
+}
+
+// step runs every registered OnObservation callback once, as the client would after
+// fetching a new observation.
+func (f *fakeAgentInfo) step() {
+	for _, fn := range f.observationHooks {
+		fn()
+	}
+}
+
+func (f *fakeAgentInfo) Observation() *api.ResponseObservation {
+	return f.obs
+}
+
+func (f *fakeAgentInfo) PlayerID() api.PlayerID {
+	return f.player
+}
+
+func (f *fakeAgentInfo) SendActions(actions []*api.Action) []api.ActionResult {
+	f.sent = actions
+	return f.results
+}
@@ -0,0 +1,188 @@
+package botutil
+
+import (
+	"strings"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// PlayerID identifies the sender of a chat message.
+type PlayerID int32
+
+// ChatScope restricts who a chat command is usable by. api.ChatReceived carries no
+// channel of its own (just PlayerId/Message), so scope isn't read off the incoming
+// message's channel; it's enforced by checking the sender's PlayerID against the
+// trusted set registered with AddTeammate.
+type ChatScope int
+
+const (
+	// ChatScopeBroadcast allows the command to be issued by anyone, including opponents
+	// and observers.
+	ChatScopeBroadcast ChatScope = iota
+	// ChatScopeTeam restricts the command to senders trusted via AddTeammate (the bot
+	// itself, by default), e.g. for commands meant to be triggered by a coach driving
+	// the bot rather than an opponent.
+	ChatScopeTeam
+)
+
+// ChatCommandHandler is the handler function type for a registered chat command.
+type ChatCommandHandler func(args []string, sender PlayerID)
+
+// chatCommand is the bookkeeping for a single registered command.
+type chatCommand struct {
+	usage   string
+	scope   ChatScope
+	handler ChatCommandHandler
+}
+
+// ChatCommands parses incoming chat messages and routes `/`-prefixed ones to
+// registered handlers, so a bot can expose runtime toggles (e.g. to a coach on ladder
+// replays) without recompiling.
+type ChatCommands struct {
+	info      client.AgentInfo
+	actions   *Actions
+	prefix    string
+	commands  map[string]chatCommand
+	teammates map[PlayerID]bool
+	unknown   ChatCommandHandler
+}
+
+// NewChatCommands creates a new ChatCommands dispatcher with the default "/" prefix.
+// The bot's own player ID is trusted for ChatScopeTeam commands by default; use
+// AddTeammate to also trust a coach or ally's player ID. It's registered with
+// OnObservation so it sees every chat message reported by a new observation.
+func NewChatCommands(info client.AgentInfo, actions *Actions) *ChatCommands {
+	cc := &ChatCommands{
+		info:      info,
+		actions:   actions,
+		prefix:    "/",
+		commands:  map[string]chatCommand{},
+		teammates: map[PlayerID]bool{PlayerID(info.PlayerID()): true},
+	}
+	cc.Register("help", "[command] - list available commands or show usage for one", ChatScopeBroadcast, cc.help)
+	info.OnObservation(cc.poll)
+	return cc
+}
+
+// SetPrefix changes the prefix that marks a chat message as a command. The default is "/".
+func (cc *ChatCommands) SetPrefix(prefix string) {
+	cc.prefix = prefix
+}
+
+// AddTeammate marks id as trusted for ChatScopeTeam commands, e.g. a coach's observer
+// player ID or an ally's in a multiplayer game.
+func (cc *ChatCommands) AddTeammate(id PlayerID) {
+	cc.teammates[id] = true
+}
+
+// Register adds a named command. usage is a short description shown by the built-in
+// help command. scope restricts who may invoke it.
+func (cc *ChatCommands) Register(name, usage string, scope ChatScope, handler ChatCommandHandler) {
+	cc.commands[name] = chatCommand{usage: usage, scope: scope, handler: handler}
+}
+
+// OnUnknown sets a handler that's called when a message uses the command prefix but
+// doesn't match any registered command name.
+func (cc *ChatCommands) OnUnknown(handler ChatCommandHandler) {
+	cc.unknown = handler
+}
+
+// help is the built-in "help" command: with no args it lists all command names, and
+// with a command name it prints that command's usage string.
+func (cc *ChatCommands) help(args []string, sender PlayerID) {
+	if len(args) > 0 {
+		if cmd, ok := cc.commands[args[0]]; ok {
+			cc.reply(sender, args[0]+" "+cmd.usage)
+			return
+		}
+		cc.reply(sender, "unknown command: "+args[0])
+		return
+	}
+
+	names := make([]string, 0, len(cc.commands))
+	for name := range cc.commands {
+		names = append(names, name)
+	}
+	cc.reply(sender, "commands: "+strings.Join(names, ", "))
+}
+
+// reply sends msg back over the team channel so the command's output is only visible
+// to teammates and observers, regardless of which channel the command itself came in on.
+func (cc *ChatCommands) reply(sender PlayerID, msg string) {
+	cc.actions.ChatTeam(msg)
+}
+
+// poll is registered as an OnObservation callback. Observation().GetChat() reports
+// only the chat messages received since the previous step, so each one is dispatched
+// exactly once, in order.
+func (cc *ChatCommands) poll() {
+	for _, chat := range cc.info.Observation().GetChat() {
+		cc.dispatch(chat)
+	}
+}
+
+// dispatch recognizes prefixed messages, tokenizes them with shell-style quoting, and
+// routes them to the matching command.
+func (cc *ChatCommands) dispatch(chat *api.ChatReceived) {
+	msg := chat.GetMessage()
+	if !strings.HasPrefix(msg, cc.prefix) {
+		return
+	}
+
+	tokens := tokenize(strings.TrimPrefix(msg, cc.prefix))
+	if len(tokens) == 0 {
+		return
+	}
+
+	name, args := tokens[0], tokens[1:]
+	sender := PlayerID(chat.GetPlayerId())
+
+	cmd, ok := cc.commands[name]
+	if !ok {
+		if cc.unknown != nil {
+			cc.unknown(tokens, sender)
+		}
+		return
+	}
+	if cmd.scope == ChatScopeTeam && !cc.teammates[sender] {
+		return
+	}
+	cmd.handler(args, sender)
+}
+
+// tokenize splits s into fields, honoring single and double quotes so that a quoted
+// argument can contain spaces (e.g. `/say "attack now"`).
+func tokenize(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	var quote rune
+	inToken := false
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t':
+			if inToken {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				inToken = false
+			}
+		default:
+			cur.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inToken {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
@@ -0,0 +1,79 @@
+package botutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single word", "attack", []string{"attack"}},
+		{"multiple words", "say hello there", []string{"say", "hello", "there"}},
+		{"double-quoted arg", `say "attack now"`, []string{"say", "attack now"}},
+		{"single-quoted arg", `say 'attack now'`, []string{"say", "attack now"}},
+		{"collapses extra whitespace", "  say   hi  ", []string{"say", "hi"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := tokenize(c.in)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("tokenize(%q) = %#v, want %#v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestChatCommandsDispatchScope(t *testing.T) {
+	info := &fakeAgentInfo{player: 1}
+	actions := &Actions{info: info}
+	cc := NewChatCommands(info, actions)
+
+	var lastArgs []string
+	cc.Register("toggle", "toggle something", ChatScopeTeam, func(args []string, sender PlayerID) {
+		lastArgs = args
+	})
+
+	info.obs = &api.ResponseObservation{
+		Chat: []*api.ChatReceived{
+			{PlayerId: 1, Message: "/toggle defend"},
+			{PlayerId: 2, Message: "/toggle rush"}, // not a trusted sender, should be ignored
+		},
+	}
+	info.step()
+
+	if lastArgs == nil {
+		t.Fatal("expected the trusted sender's command to run")
+	}
+	if len(lastArgs) != 1 || lastArgs[0] != "defend" {
+		t.Errorf("expected args [defend], got %v", lastArgs)
+	}
+}
+
+func TestChatCommandsAddTeammate(t *testing.T) {
+	info := &fakeAgentInfo{player: 1}
+	actions := &Actions{info: info}
+	cc := NewChatCommands(info, actions)
+	cc.AddTeammate(7)
+
+	var ran bool
+	cc.Register("coach", "coach-only command", ChatScopeTeam, func(args []string, sender PlayerID) {
+		ran = true
+	})
+
+	info.obs = &api.ResponseObservation{
+		Chat: []*api.ChatReceived{{PlayerId: 7, Message: "/coach"}},
+	}
+	info.step()
+
+	if !ran {
+		t.Error("expected a registered teammate to be able to run a ChatScopeTeam command")
+	}
+}
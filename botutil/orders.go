@@ -0,0 +1,226 @@
+package botutil
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// orderTargetThreshold is how far (in world units) a dynamic target has to move
+// before a standing order is re-issued to refresh it.
+const orderTargetThreshold = 1.0
+
+// reissueInterval bounds how long a standing order can go without being refreshed.
+// Even when its squad and target are unchanged, an order is periodically reissued so
+// that units which went idle or lost the queued order (e.g. after finishing it, or
+// getting bumped off it by an enemy ability) pick it back up.
+const reissueInterval = 8
+
+// OrderHandle identifies a standing order previously registered with Orders.AddOrder.
+type OrderHandle string
+
+// OrderCompletedHandler is the handler function type for standing order completion.
+type OrderCompletedHandler func(name string, reason string)
+
+// orderTargetKind distinguishes the way an OrderTarget was constructed, so update()
+// can tell a target that was never set from a unit target that's since died.
+type orderTargetKind int
+
+const (
+	orderTargetNone orderTargetKind = iota
+	orderTargetUnit
+	orderTargetPos
+	orderTargetFunc
+)
+
+// OrderTarget is the target of a standing order: a specific unit, a fixed position,
+// or a position that's recomputed every step (e.g. to follow a rally point).
+type OrderTarget struct {
+	kind orderTargetKind
+	unit Unit
+	pos  *api.Point2D
+	fn   func() *api.Point2D
+}
+
+// TargetUnit creates an OrderTarget that follows a specific unit.
+func TargetUnit(u Unit) OrderTarget {
+	return OrderTarget{kind: orderTargetUnit, unit: u}
+}
+
+// TargetPos creates an OrderTarget fixed at a world space position.
+func TargetPos(pos *api.Point2D) OrderTarget {
+	return OrderTarget{kind: orderTargetPos, pos: pos}
+}
+
+// TargetFunc creates an OrderTarget that's recomputed every step.
+func TargetFunc(fn func() *api.Point2D) OrderTarget {
+	return OrderTarget{kind: orderTargetFunc, fn: fn}
+}
+
+// resolve returns the target's current world space position. It returns nil if it's a
+// unit target whose unit is no longer present, or a func target with nothing to report
+// yet; either way the caller should not treat a nil result as "no target requested".
+func (t OrderTarget) resolve() *api.Point2D {
+	switch t.kind {
+	case orderTargetFunc:
+		return t.fn()
+	case orderTargetUnit:
+		if t.unit.IsNil() {
+			return nil
+		}
+		return t.unit.GetPos()
+	case orderTargetPos:
+		return t.pos
+	default:
+		return nil
+	}
+}
+
+// OrderSpec describes a standing order: the squad to command, the ability to use, and
+// what to use it on. Squad is called every step to get the units that should currently
+// be following the order, so it should reflect unit deaths and reinforcements (e.g. by
+// re-filtering a persistent selector) rather than returning a fixed snapshot.
+type OrderSpec struct {
+	Squad   func() Units
+	Ability api.AbilityID
+	Target  OrderTarget
+}
+
+// order is the live bookkeeping for a single standing order.
+type order struct {
+	spec       OrderSpec
+	squadTags  map[api.UnitTag]bool
+	lastTarget *api.Point2D
+	ticks      int
+}
+
+// Orders lets bot authors register standing orders against a named squad of units,
+// e.g. "squad Alpha attack-moves to point P until disbanded". Registered orders are
+// automatically re-issued as the squad or its target changes, removing the need to
+// manually re-queue movement/attack commands every step.
+type Orders struct {
+	info    client.AgentInfo
+	actions *Actions
+	orders  map[string]*order
+	onDone  OrderCompletedHandler
+}
+
+// NewOrders creates a new Orders manager. Its update method is registered to be
+// automatically called before each client Step(). It is registered after the Send()
+// of an Actions created with NewActions, so the commands it queues go out with the
+// following Send().
+func NewOrders(info client.AgentInfo, actions *Actions) *Orders {
+	o := &Orders{info: info, actions: actions, orders: map[string]*order{}}
+	info.OnBeforeStep(o.update)
+	return o
+}
+
+// AddOrder registers a standing order under name, replacing any existing order with
+// the same name.
+func (o *Orders) AddOrder(name string, spec OrderSpec) OrderHandle {
+	o.orders[name] = &order{spec: spec}
+	return OrderHandle(name)
+}
+
+// CancelOrder removes a standing order without issuing a stop/hold command to its squad.
+func (o *Orders) CancelOrder(name string) {
+	delete(o.orders, name)
+}
+
+// ListOrders returns the names of all currently registered standing orders.
+func (o *Orders) ListOrders() []string {
+	names := make([]string, 0, len(o.orders))
+	for name := range o.orders {
+		names = append(names, name)
+	}
+	return names
+}
+
+// OnOrderCompleted sets a handler that's called whenever update drops a standing order,
+// along with a short reason (e.g. "squad empty" or "target lost"). It is not called for
+// orders removed with CancelOrder.
+func (o *Orders) OnOrderCompleted(handler OrderCompletedHandler) {
+	o.onDone = handler
+}
+
+// update re-evaluates every standing order: it prunes dead tags from the squad,
+// completes orders whose squad or unit target has been wiped out, and re-issues the
+// command whenever the squad or its target has changed, or reissueInterval steps have
+// passed since the last issue (to pick up units that went idle or lost the order).
+func (o *Orders) update() {
+	for name, ord := range o.orders {
+		squad := ord.spec.Squad()
+		tags := squad.Tags()
+		if len(tags) == 0 {
+			delete(o.orders, name)
+			if o.onDone != nil {
+				o.onDone(name, "squad empty")
+			}
+			continue
+		}
+
+		target := ord.spec.Target
+		pos := target.resolve()
+		if target.kind == orderTargetUnit && pos == nil {
+			delete(o.orders, name)
+			if o.onDone != nil {
+				o.onDone(name, "target lost")
+			}
+			continue
+		}
+		if pos == nil && (target.kind == orderTargetPos || target.kind == orderTargetFunc) {
+			// Nothing resolvable this step (e.g. a TargetFunc with no opinion yet);
+			// wait rather than issue a bare, untargeted command.
+			continue
+		}
+
+		ord.ticks++
+		unchanged := tagsEqual(ord.squadTags, tags) && !targetMoved(ord.lastTarget, pos)
+		if unchanged && ord.ticks%reissueInterval != 0 {
+			continue
+		}
+		ord.squadTags = tagSet(tags)
+		ord.lastTarget = pos
+
+		switch target.kind {
+		case orderTargetUnit:
+			o.actions.unitsOrderTarget(tags, ord.spec.Ability, target.unit)
+		case orderTargetPos, orderTargetFunc:
+			o.actions.unitsOrderPos(tags, ord.spec.Ability, pos)
+		default:
+			o.actions.unitsOrder(tags, ord.spec.Ability)
+		}
+	}
+}
+
+// targetMoved reports whether pos has moved beyond orderTargetThreshold from last.
+func targetMoved(last, pos *api.Point2D) bool {
+	if last == nil || pos == nil {
+		return last != pos
+	}
+	dx := last.X - pos.X
+	dy := last.Y - pos.Y
+	return dx*dx+dy*dy > orderTargetThreshold*orderTargetThreshold
+}
+
+// tagSet builds a lookup set from a slice of unit tags.
+func tagSet(tags []api.UnitTag) map[api.UnitTag]bool {
+	set := make(map[api.UnitTag]bool, len(tags))
+	for _, t := range tags {
+		set[t] = true
+	}
+	return set
+}
+
+// tagsEqual reports whether tags has exactly the members of set, so a squad that lost
+// and gained the same number of units (but not the same units) is seen as changed.
+func tagsEqual(set map[api.UnitTag]bool, tags []api.UnitTag) bool {
+	if len(set) != len(tags) {
+		return false
+	}
+	for _, t := range tags {
+		if !set[t] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package botutil
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestTagsEqual(t *testing.T) {
+	set := tagSet([]api.UnitTag{1, 2, 3})
+
+	if !tagsEqual(set, []api.UnitTag{3, 2, 1}) {
+		t.Error("expected the same tags in a different order to be equal")
+	}
+	if tagsEqual(set, []api.UnitTag{1, 2}) {
+		t.Error("expected a smaller squad to be unequal")
+	}
+	if tagsEqual(set, []api.UnitTag{1, 2, 4}) {
+		t.Error("expected a same-size squad with a replaced tag to be unequal")
+	}
+	if !tagsEqual(tagSet(nil), nil) {
+		t.Error("expected two empty tag sets to compare equal")
+	}
+}
+
+func TestTargetMoved(t *testing.T) {
+	origin := &api.Point2D{X: 0, Y: 0}
+	near := &api.Point2D{X: 0.5, Y: 0}
+	far := &api.Point2D{X: 5, Y: 0}
+
+	if targetMoved(origin, near) {
+		t.Error("expected a move under orderTargetThreshold to not count as moved")
+	}
+	if !targetMoved(origin, far) {
+		t.Error("expected a move past orderTargetThreshold to count as moved")
+	}
+	if !targetMoved(nil, origin) {
+		t.Error("expected a newly resolved target to count as moved")
+	}
+	if !targetMoved(origin, nil) {
+		t.Error("expected a target that stopped resolving to count as moved")
+	}
+	if targetMoved(nil, nil) {
+		t.Error("expected two unresolved targets to not count as moved")
+	}
+}
+
+func TestOrderTargetResolve(t *testing.T) {
+	pos := &api.Point2D{X: 1, Y: 2}
+
+	if got := TargetPos(pos).resolve(); got != pos {
+		t.Errorf("TargetPos: expected resolve() to return the given position, got %v", got)
+	}
+	if got := TargetFunc(func() *api.Point2D { return pos }).resolve(); got != pos {
+		t.Errorf("TargetFunc: expected resolve() to return the func's result, got %v", got)
+	}
+	if got := TargetFunc(func() *api.Point2D { return nil }).resolve(); got != nil {
+		t.Errorf("TargetFunc: expected a nil result to pass through, got %v", got)
+	}
+	if got := (OrderTarget{}).resolve(); got != nil {
+		t.Errorf("zero-value OrderTarget: expected resolve() to return nil, got %v", got)
+	}
+}